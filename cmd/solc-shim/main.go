@@ -0,0 +1,126 @@
+// Copyright (c) The Amphitheatre Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command solc-shim is installed as bin/solc by a multi-version Solc layer. It scans its
+// file arguments for `pragma solidity` directives, resolves the highest installed version
+// satisfying all of them, and execs the matching bin/solc-<version> binary in its place.
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// pragmaPattern matches a Solidity `pragma solidity <constraint>;` directive, capturing
+// the constraint expression.
+var pragmaPattern = regexp.MustCompile(`pragma\s+solidity\s+([^;]+);`)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "solc-shim: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	versions := strings.FieldsFunc(os.Getenv("SOLC_SHIM_VERSIONS"), func(r rune) bool { return r == ',' })
+	if len(versions) == 0 {
+		return fmt.Errorf("$SOLC_SHIM_VERSIONS is empty; no solc versions are installed")
+	}
+
+	constraints, err := constraintsFromFiles(args)
+	if err != nil {
+		return err
+	}
+
+	version, err := resolve(versions, constraints)
+	if err != nil {
+		return err
+	}
+
+	bin := os.Getenv("SOLC_SHIM_BIN")
+	if bin == "" {
+		bin = "solc"
+	}
+	target := fmt.Sprintf("%s-%s", bin, version)
+
+	path, err := exec.LookPath(target)
+	if err != nil {
+		return fmt.Errorf("unable to locate %s on $PATH\n%w", target, err)
+	}
+
+	return syscall.Exec(path, append([]string{target}, args...), os.Environ())
+}
+
+// constraintsFromFiles extracts every `pragma solidity` constraint found across path,
+// ignoring files it cannot read (they are most likely solc flags, not source paths).
+func constraintsFromFiles(paths []string) ([]*semver.Constraints, error) {
+	var constraints []*semver.Constraints
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		for _, m := range pragmaPattern.FindAllStringSubmatch(string(content), -1) {
+			c, err := semver.NewConstraint(normalize(m[1]))
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse pragma solidity constraint %q in %s\n%w", m[1], path, err)
+			}
+			constraints = append(constraints, c)
+		}
+	}
+	return constraints, nil
+}
+
+// normalize rewrites Solidity's caret/range pragma syntax (e.g. `^0.8.0`, `>=0.8.0 <0.9.0`)
+// into a form Masterminds/semver accepts, which in practice is already compatible.
+func normalize(constraint string) string {
+	return strings.TrimSpace(constraint)
+}
+
+// resolve picks the highest version in versions that satisfies every constraint.
+func resolve(versions []string, constraints []*semver.Constraints) (string, error) {
+	parsed := make([]*semver.Version, 0, len(versions))
+	for _, v := range versions {
+		sv, err := semver.NewVersion(strings.TrimSpace(v))
+		if err != nil {
+			return "", fmt.Errorf("unable to parse installed version %q\n%w", v, err)
+		}
+		parsed = append(parsed, sv)
+	}
+	sort.Sort(sort.Reverse(semver.Collection(parsed)))
+
+	for _, v := range parsed {
+		satisfies := true
+		for _, c := range constraints {
+			if !c.Check(v) {
+				satisfies = false
+				break
+			}
+		}
+		if satisfies {
+			return v.Original(), nil
+		}
+	}
+
+	return "", fmt.Errorf("no installed solc version (%s) satisfies the pragma solidity constraints in the given sources", strings.Join(versions, ", "))
+}
@@ -0,0 +1,102 @@
+// Copyright (c) The Amphitheatre Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+func TestResolvePicksHighestSatisfyingVersion(t *testing.T) {
+	constraint, err := semver.NewConstraint(">=0.8.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	version, err := resolve([]string{"0.7.6", "0.8.19", "0.8.25"}, []*semver.Constraints{constraint})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != "0.8.25" {
+		t.Errorf("resolve() = %q, want %q", version, "0.8.25")
+	}
+}
+
+func TestResolveIntersectsAllConstraints(t *testing.T) {
+	lower, err := semver.NewConstraint(">=0.8.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	upper, err := semver.NewConstraint("<0.8.25")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	version, err := resolve([]string{"0.7.6", "0.8.19", "0.8.25"}, []*semver.Constraints{lower, upper})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != "0.8.19" {
+		t.Errorf("resolve() = %q, want %q", version, "0.8.19")
+	}
+}
+
+func TestResolveErrorsWhenNothingSatisfies(t *testing.T) {
+	constraint, err := semver.NewConstraint(">=0.9.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := resolve([]string{"0.7.6", "0.8.25"}, []*semver.Constraints{constraint}); err == nil {
+		t.Fatal("expected an error when no installed version satisfies the constraints")
+	}
+}
+
+func TestConstraintsFromFilesParsesPragmas(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Example.sol")
+	if err := os.WriteFile(path, []byte("pragma solidity ^0.8.0;\ncontract Example {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	constraints, err := constraintsFromFiles([]string{path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(constraints) != 1 {
+		t.Fatalf("len(constraints) = %d, want 1", len(constraints))
+	}
+
+	v, err := semver.NewVersion("0.8.25")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !constraints[0].Check(v) {
+		t.Error("expected ^0.8.0 constraint to accept 0.8.25")
+	}
+}
+
+func TestConstraintsFromFilesIgnoresUnreadablePaths(t *testing.T) {
+	constraints, err := constraintsFromFiles([]string{"--combined-json", "/does/not/exist.sol"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(constraints) != 0 {
+		t.Errorf("len(constraints) = %d, want 0", len(constraints))
+	}
+}
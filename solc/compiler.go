@@ -0,0 +1,316 @@
+// Copyright (c) The Amphitheatre Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solc
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/paketo-buildpacks/libpak"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/paketo-buildpacks/libpak/effect"
+)
+
+// combinedJSONSelectors is passed to `solc --combined-json` to produce the ABI, bytecode
+// and documentation artifacts that downstream deploy tooling expects.
+const combinedJSONSelectors = "abi,bin,bin-runtime,srcmap,srcmap-runtime,userdoc,devdoc,metadata"
+
+// Compiler is a contributor that compiles the application's Solidity sources with solc
+// and writes the resulting ABI/bytecode artifacts to a launch-visible layer.
+type Compiler struct {
+	LayerContributor libpak.LayerContributor
+	Logger           bard.Logger
+	Executor         effect.Executor
+	AppPath          string
+	Sources          []string
+	Excludes         []string
+}
+
+// NewCompiler creates a Compiler that discovers Solidity sources under appPath, honoring
+// the given include/exclude glob patterns (typically sourced from $BP_SOLC_SOURCES and
+// $BP_SOLC_EXCLUDES).
+func NewCompiler(appPath string, sources []string, excludes []string) Compiler {
+	c := Compiler{
+		Executor: effect.NewExecutor(),
+		AppPath:  appPath,
+		Sources:  sources,
+		Excludes: excludes,
+	}
+
+	digest, err := c.sourcesDigest()
+	if err != nil {
+		// discovery failing here just means the layer can't be fingerprinted by content;
+		// IsRequired/Contribute will surface the same error properly later.
+		digest = ""
+	}
+
+	c.LayerContributor = libpak.NewLayerContributor("Solidity Contracts", map[string]interface{}{
+		"sources":  sources,
+		"excludes": excludes,
+		"digest":   digest,
+	}, libcnb.LayerTypes{
+		Launch: true,
+	})
+	return c
+}
+
+// IsRequired reports whether the application looks like a Solidity project, either
+// because it has .sol sources directly or is driven by a known framework config.
+func (r Compiler) IsRequired() (bool, error) {
+	frameworks := []string{"foundry.toml", "hardhat.config.js", "hardhat.config.ts", "truffle-config.js"}
+	for _, f := range frameworks {
+		if _, err := os.Stat(filepath.Join(r.AppPath, f)); err == nil {
+			return true, nil
+		} else if !os.IsNotExist(err) {
+			return false, fmt.Errorf("unable to stat %s\n%w", f, err)
+		}
+	}
+
+	sources, err := r.discover()
+	if err != nil {
+		return false, err
+	}
+	return len(sources) > 0, nil
+}
+
+// discover walks AppPath for .sol files, applying Sources as an include allow-list
+// (defaulting to all files) and Excludes as a deny-list, both matched as "**"-capable globs
+// against the path relative to AppPath.
+func (r Compiler) discover() ([]string, error) {
+	var sources []string
+	err := filepath.Walk(r.AppPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == "node_modules" || info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".sol" {
+			return nil
+		}
+
+		rel, err := filepath.Rel(r.AppPath, path)
+		if err != nil {
+			return err
+		}
+
+		if len(r.Sources) > 0 && !matchesAny(rel, r.Sources) {
+			return nil
+		}
+		if matchesAny(rel, r.Excludes) {
+			return nil
+		}
+
+		sources = append(sources, path)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to walk %s\n%w", r.AppPath, err)
+	}
+	return sources, nil
+}
+
+func matchesAny(path string, patterns []string) bool {
+	for _, p := range patterns {
+		if matchGlob(p, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob reports whether path matches pattern, where pattern is a slash-separated glob
+// that in addition to filepath.Match's "*"/"?"/"[...]" within a segment, supports "**" as a
+// segment that matches zero or more path segments (e.g. "contracts/**/*.sol"). This is
+// needed because filepath.Match never crosses "/", which would otherwise make recursive
+// $BP_SOLC_SOURCES/$BP_SOLC_EXCLUDES patterns match nothing.
+func matchGlob(pattern string, path string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(path, "/"))
+}
+
+func matchSegments(pattern []string, path []string) bool {
+	if len(pattern) == 0 {
+		return len(path) == 0
+	}
+
+	if pattern[0] == "**" {
+		if matchSegments(pattern[1:], path) {
+			return true
+		}
+		if len(path) == 0 {
+			return false
+		}
+		return matchSegments(pattern, path[1:])
+	}
+
+	if len(path) == 0 {
+		return false
+	}
+
+	if ok, err := filepath.Match(pattern[0], path[0]); err != nil || !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], path[1:])
+}
+
+// allowPaths returns the --allow-paths value: the app root plus node_modules, so that
+// OpenZeppelin-style `import "@openzeppelin/..."` resolves.
+func (r Compiler) allowPaths() string {
+	paths := []string{r.AppPath}
+	if nodeModules := filepath.Join(r.AppPath, "node_modules"); dirExists(nodeModules) {
+		paths = append(paths, nodeModules)
+	}
+	return strings.Join(paths, ",")
+}
+
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// sourcesDigest fingerprints the discovered sources' content (size and modification time,
+// rather than hashing every byte), so that the layer is recompiled when a .sol file changes
+// even though Sources/Excludes themselves (the glob patterns) did not.
+func (r Compiler) sourcesDigest() (string, error) {
+	sources, err := r.discover()
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(sources)
+
+	h := sha256.New()
+	for _, path := range sources {
+		info, err := os.Stat(path)
+		if err != nil {
+			return "", fmt.Errorf("unable to stat %s\n%w", path, err)
+		}
+		fmt.Fprintf(h, "%s:%d:%d\n", path, info.Size(), info.ModTime().UnixNano())
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func (r Compiler) Contribute(layer libcnb.Layer) (libcnb.Layer, error) {
+	r.LayerContributor.Logger = r.Logger
+	return r.LayerContributor.Contribute(layer, func() (libcnb.Layer, error) {
+		sources, err := r.discover()
+		if err != nil {
+			return libcnb.Layer{}, err
+		}
+		if len(sources) == 0 {
+			return libcnb.Layer{}, fmt.Errorf("no Solidity sources found under %s matching sources=%v excludes=%v", r.AppPath, r.Sources, r.Excludes)
+		}
+
+		args := []string{"--combined-json", combinedJSONSelectors, "--allow-paths", r.allowPaths()}
+		args = append(args, sources...)
+
+		r.Logger.Bodyf("Compiling %d Solidity source(s)", len(sources))
+		buf := &bytes.Buffer{}
+		errBuf := &bytes.Buffer{}
+		if err := r.Executor.Execute(effect.Execution{
+			Command: "solc",
+			Args:    args,
+			Dir:     r.AppPath,
+			Stdout:  buf,
+			Stderr:  errBuf,
+		}); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("error executing 'solc --combined-json %s':\n Combined Output: %s: \n%w", combinedJSONSelectors, errBuf.String(), err)
+		}
+
+		combinedPath := filepath.Join(layer.Path, "combined.json")
+		if err := os.WriteFile(combinedPath, buf.Bytes(), 0644); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to write %s\n%w", combinedPath, err)
+		}
+
+		var combined struct {
+			Contracts map[string]json.RawMessage `json:"contracts"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &combined); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to parse %s\n%w", combinedPath, err)
+		}
+
+		contracts := filepath.Join(layer.Path, "contracts")
+		if err := os.MkdirAll(contracts, 0755); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to create %s\n%w", contracts, err)
+		}
+
+		for name, raw := range combined.Contracts {
+			// name is of the form "path/to/Source.sol:ContractName"; keep the full name
+			// (rather than just ContractName) so that same-named contracts defined in
+			// different source files don't overwrite each other's artifact.
+			artifact := filepath.Join(contracts, sanitizeContractName(name)+".json")
+			if err := os.MkdirAll(filepath.Dir(artifact), 0755); err != nil {
+				return libcnb.Layer{}, fmt.Errorf("unable to create %s\n%w", filepath.Dir(artifact), err)
+			}
+			if err := os.WriteFile(artifact, raw, 0644); err != nil {
+				return libcnb.Layer{}, fmt.Errorf("unable to write %s\n%w", artifact, err)
+			}
+		}
+
+		layer.LaunchEnvironment.Default("SOLC_OUTPUT_DIR", layer.Path)
+		return layer, nil
+	})
+}
+
+// sanitizeContractName turns a combined-json key of the form "path/to/Source.sol:Contract"
+// into a filesystem-safe relative path "path/to/Source.sol/Contract", nesting the artifact
+// under its source file so that same-named contracts in different files don't collide.
+func sanitizeContractName(name string) string {
+	i := strings.LastIndex(name, ":")
+	if i < 0 {
+		return name
+	}
+	return filepath.Join(name[:i], name[i+1:])
+}
+
+// BuildProcessTypes exposes a "compile" process that re-runs solc against the same
+// sources Contribute compiled at build time, writing combined.json to $SOLC_OUTPUT_DIR (so
+// it matches what Contribute already produced) rather than stdout. It is omitted entirely
+// when there are no discovered sources, since solc errors on an empty file list.
+func (r Compiler) BuildProcessTypes() ([]libcnb.Process, error) {
+	sources, err := r.discover()
+	if err != nil {
+		return nil, err
+	}
+	if len(sources) == 0 {
+		return []libcnb.Process{}, nil
+	}
+
+	args := []string{"--combined-json", combinedJSONSelectors, "--allow-paths", r.allowPaths(), "-o", "$SOLC_OUTPUT_DIR", "--overwrite"}
+	args = append(args, sources...)
+
+	return []libcnb.Process{
+		{
+			Type:    "compile",
+			Command: "solc",
+			Args:    args,
+			Default: false,
+		},
+	}, nil
+}
+
+func (r Compiler) Name() string {
+	return r.LayerContributor.Name
+}
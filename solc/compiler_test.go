@@ -0,0 +1,54 @@
+// Copyright (c) The Amphitheatre Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solc
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"contracts/*.sol", "contracts/Token.sol", true},
+		{"contracts/*.sol", "contracts/nested/Token.sol", false},
+		{"contracts/**/*.sol", "contracts/Token.sol", true},
+		{"contracts/**/*.sol", "contracts/nested/Token.sol", true},
+		{"contracts/**/*.sol", "contracts/a/b/c/Token.sol", true},
+		{"contracts/**/*.sol", "lib/Token.sol", false},
+		{"**/*.sol", "Token.sol", true},
+		{"**/*.sol", "contracts/nested/Token.sol", true},
+	}
+
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestSanitizeContractName(t *testing.T) {
+	tests := map[string]string{
+		"contracts/Token.sol:Token":        "contracts/Token.sol/Token",
+		"contracts/nested/Other.sol:Token": "contracts/nested/Other.sol/Token",
+		"NoColon":                          "NoColon",
+	}
+
+	for in, want := range tests {
+		if got := sanitizeContractName(in); got != want {
+			t.Errorf("sanitizeContractName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright (c) The Amphitheatre Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solc
+
+import (
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+)
+
+// NPMPackage pins an npm package to a specific, integrity-verified tarball, mirroring
+// the fields a package-lock.json "resolved"/"integrity" entry carries.
+type NPMPackage struct {
+	Name      string `toml:"name"`
+	Version   string `toml:"version"`
+	Resolved  string `toml:"resolved"`
+	Integrity string `toml:"integrity"`
+}
+
+// npmLockDocument is the shape of the [[metadata.npm-packages]] table in buildpack.toml,
+// or of a standalone solc.lock file using the same table name.
+type npmLockDocument struct {
+	Metadata struct {
+		NPMPackages []NPMPackage `toml:"npm-packages"`
+	} `toml:"metadata"`
+}
+
+// ParseNPMLock reads the npm package pins from path, which may be buildpack.toml itself
+// or a sibling solc.lock file using the same [[metadata.npm-packages]] table.
+func ParseNPMLock(path string) ([]NPMPackage, error) {
+	var doc npmLockDocument
+	if _, err := toml.DecodeFile(path, &doc); err != nil {
+		return nil, fmt.Errorf("unable to decode %s\n%w", path, err)
+	}
+	return doc.Metadata.NPMPackages, nil
+}
+
+// Find returns the pinned entry for name@version, if any.
+func Find(packages []NPMPackage, name string, version string) (NPMPackage, bool) {
+	for _, p := range packages {
+		if p.Name == name && p.Version == version {
+			return p, true
+		}
+	}
+	return NPMPackage{}, false
+}
@@ -0,0 +1,49 @@
+// Copyright (c) The Amphitheatre Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solc
+
+import "testing"
+
+func TestCoreVersion(t *testing.T) {
+	tests := map[string]string{
+		"0.8.25+commit.b61c2a91.Linux.g++": "0.8.25",
+		"0.8.25":                           "0.8.25",
+		"not-a-version":                    "not-a-version",
+	}
+
+	for in, want := range tests {
+		if got := coreVersion(in); got != want {
+			t.Errorf("coreVersion(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFindMatchesCoreVersion(t *testing.T) {
+	packages := []NPMPackage{
+		{Name: "solc", Version: "0.8.25", Resolved: "https://registry.npmjs.org/solc/-/solc-0.8.25.tgz", Integrity: "sha512-abcd"},
+	}
+
+	pkg, ok := Find(packages, "solc", coreVersion("0.8.25+commit.b61c2a91.Linux.g++"))
+	if !ok {
+		t.Fatal("expected to find pinned entry for solcjs's full version string")
+	}
+	if pkg.Integrity != "sha512-abcd" {
+		t.Errorf("Integrity = %q, want %q", pkg.Integrity, "sha512-abcd")
+	}
+
+	if _, ok := Find(packages, "solc", "0.7.6"); ok {
+		t.Error("expected no match for an unpinned version")
+	}
+}
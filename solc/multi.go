@@ -0,0 +1,248 @@
+// Copyright (c) The Amphitheatre Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solc
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/paketo-buildpacks/libpak"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/paketo-buildpacks/libpak/effect"
+	"github.com/paketo-buildpacks/libpak/sbom"
+	"github.com/paketo-buildpacks/libpak/sherpa"
+)
+
+// copyFile copies the regular file at src to dest, creating dest with perm.
+func copyFile(src string, dest string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	return err
+}
+
+// writeExecShim writes a tiny shell script at path that execs target with whatever
+// arguments it was called with, so bin/solc-<version> can point at a solcjs wrapper that
+// lives (with its sibling lib/node_modules/solc package) elsewhere in the layer.
+func writeExecShim(path string, target string) error {
+	script := fmt.Sprintf("#!/bin/sh\nexec %q \"$@\"\n", target)
+	return os.WriteFile(path, []byte(script), 0755)
+}
+
+// MultiSolc contributes several solc versions side-by-side into one layer, fronted by a
+// bin/solc shim (see cmd/solc-shim) that picks the right one per invocation based on the
+// `pragma solidity` directives in the files it is passed. It is the contributor behind
+// $BP_SOLC_VERSIONS, where a monorepo pins different versions per contract.
+type MultiSolc struct {
+	LayerContributor libpak.LayerContributor
+	Logger           bard.Logger
+	Executor         effect.Executor
+	Dependencies     []libpak.BuildpackDependency
+	Cache            libpak.DependencyCache
+	Distribution     Distribution
+
+	// ShimPath is the path to the solc-shim binary (cmd/solc-shim), pre-compiled and
+	// shipped alongside this buildpack's own `bin/main`, `bin/build` and `bin/detect`.
+	ShimPath string
+}
+
+// NewMultiSolc creates a MultiSolc that installs every dependency in dependencies,
+// each resolved from $BP_SOLC_VERSIONS against the versions declared in buildpack.toml.
+func NewMultiSolc(dependencies []libpak.BuildpackDependency, cache libpak.DependencyCache) MultiSolc {
+	versions := make([]string, 0, len(dependencies))
+	for _, d := range dependencies {
+		versions = append(versions, d.Version)
+	}
+
+	contributor := libpak.NewLayerContributor("Solc (multi-version)", map[string]interface{}{
+		"versions": versions,
+	}, libcnb.LayerTypes{
+		Build:  true,
+		Cache:  true,
+		Launch: true,
+	})
+	return MultiSolc{
+		LayerContributor: contributor,
+		Executor:         effect.NewExecutor(),
+		Dependencies:     dependencies,
+		Cache:            cache,
+		Distribution:     DistributionSolcJS,
+	}
+}
+
+func (r MultiSolc) Contribute(layer libcnb.Layer) (libcnb.Layer, error) {
+	r.LayerContributor.Logger = r.Logger
+	return r.LayerContributor.Contribute(layer, func() (libcnb.Layer, error) {
+		bin := filepath.Join(layer.Path, "bin")
+		if err := os.MkdirAll(bin, 0755); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to create %s\n%w", bin, err)
+		}
+
+		var versions []string
+		var artifacts []sbom.SyftArtifact
+		for _, dependency := range r.Dependencies {
+			version, err := r.install(layer, bin, dependency)
+			if err != nil {
+				return libcnb.Layer{}, err
+			}
+			versions = append(versions, version)
+			artifacts = append(artifacts, r.artifact(version))
+		}
+
+		if err := r.writeSBOM(layer, artifacts); err != nil {
+			return libcnb.Layer{}, err
+		}
+
+		shim := filepath.Join(bin, "solc")
+		r.Logger.Bodyf("Installing version-selector shim at %s for versions %s", shim, strings.Join(versions, ", "))
+		if err := copyFile(r.ShimPath, shim, 0755); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to install shim %s\n%w", shim, err)
+		}
+
+		r.Logger.Bodyf("Setting %s in PATH", bin)
+		if err := os.Setenv("PATH", sherpa.AppendToEnvVar("PATH", ":", bin)); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to set $PATH\n%w", err)
+		}
+
+		layer.BuildEnvironment.Default("SOLC_SHIM_VERSIONS", strings.Join(versions, ","))
+		layer.LaunchEnvironment.Default("SOLC_SHIM_VERSIONS", strings.Join(versions, ","))
+
+		return layer, nil
+	})
+}
+
+// install contributes a single dependency's binaries, named bin/solc-<version> (and
+// bin/solcjs-<version> for the solcjs distribution), and returns its resolved version.
+// For the solcjs distribution, the fetched dependency artifact (the solc npm package
+// tarball, pinned by SHA-256 in buildpack.toml) is installed globally via npm, which must
+// already be on $PATH, into layer.Path/versions/<version>; bin/solc(js)-<version> are shims
+// that exec into it, since the installed solcjs wrapper requires its sibling
+// lib/node_modules/solc package.
+func (r MultiSolc) install(layer libcnb.Layer, bin string, dependency libpak.BuildpackDependency) (string, error) {
+	artifact, err := r.Cache.Artifact(dependency)
+	if err != nil {
+		return "", fmt.Errorf("unable to fetch %s\n%w", dependency.Name, err)
+	}
+	defer artifact.Close()
+
+	versioned := filepath.Join(bin, fmt.Sprintf("solc-%s", dependency.Version))
+
+	if r.Distribution == DistributionNative {
+		r.Logger.Bodyf("Installing native solc %s to %s", dependency.Version, versioned)
+		out, err := os.OpenFile(versioned, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0755)
+		if err != nil {
+			return "", fmt.Errorf("unable to open %s\n%w", versioned, err)
+		}
+		defer out.Close()
+		if _, err := io.Copy(out, artifact); err != nil {
+			return "", fmt.Errorf("unable to install %s\n%w", versioned, err)
+		}
+		return dependency.Version, nil
+	}
+
+	versionDir := filepath.Join(layer.Path, "versions", dependency.Version)
+	if err := os.MkdirAll(versionDir, 0755); err != nil {
+		return "", fmt.Errorf("unable to create %s\n%w", versionDir, err)
+	}
+
+	npm, err := exec.LookPath("npm")
+	if err != nil {
+		return "", fmt.Errorf("unable to locate npm on $PATH (the solcjs distribution requires a Node.js buildpack earlier in the build plan)\n%w", err)
+	}
+
+	r.Logger.Bodyf("Installing %s globally to %s via %s", artifact.Name(), versionDir, npm)
+	buf := &bytes.Buffer{}
+	if err := r.Executor.Execute(effect.Execution{
+		Command: npm,
+		Args:    []string{"install", artifact.Name(), "-g", "--prefix", versionDir},
+		Stdout:  buf,
+		Stderr:  buf,
+	}); err != nil {
+		return "", fmt.Errorf("error executing '%s':\n Combined Output: %s: \n%w", npm, buf.String(), err)
+	}
+
+	solcjs := filepath.Join(versionDir, "bin", "solcjs")
+	versionedJS := filepath.Join(bin, fmt.Sprintf("solcjs-%s", dependency.Version))
+	if err := writeExecShim(versionedJS, solcjs); err != nil {
+		return "", fmt.Errorf("unable to install %s\n%w", versionedJS, err)
+	}
+	if err := writeExecShim(versioned, solcjs); err != nil {
+		return "", fmt.Errorf("unable to install %s\n%w", versioned, err)
+	}
+
+	return dependency.Version, nil
+}
+
+func (r MultiSolc) cpe(version string) string {
+	return fmt.Sprintf("cpe:2.3:a:solc:solc:%s:*:*:*:*:*:*:*", version)
+}
+
+func (r MultiSolc) purl(version string) string {
+	if r.Distribution == DistributionNative {
+		return fmt.Sprintf("pkg:generic/solc@%s", version)
+	}
+	return fmt.Sprintf("pkg:npm/solc@%s", version)
+}
+
+// artifact builds the Syft artifact describing one installed version; its CPEs field is
+// what aggregates into the layer's single, recognized SBOM below.
+func (r MultiSolc) artifact(version string) sbom.SyftArtifact {
+	return sbom.SyftArtifact{
+		ID:      fmt.Sprintf("solc-%s", version),
+		Name:    "Solc",
+		Version: version,
+		Type:    "UnknownPackage",
+		FoundBy: "amp-buildpacks/solc",
+		Locations: []sbom.SyftLocation{
+			{Path: "amp-buildpacks/solc/solc/multi.go"},
+		},
+		Licenses: []string{"Apache-2.0"},
+		CPEs:     []string{r.cpe(version)},
+		PURL:     r.purl(version),
+	}
+}
+
+// writeSBOM writes one Syft artifact per installed version to the layer's recognized SBOM
+// path, so the aggregate CPE list (the union of each artifact's CPEs) is ingestible by the
+// platform rather than only logged.
+func (r MultiSolc) writeSBOM(layer libcnb.Layer, artifacts []sbom.SyftArtifact) error {
+	sbomPath := layer.SBOMPath(libcnb.SyftJSON)
+	dep := sbom.NewSyftDependency(layer.Path, artifacts)
+	r.Logger.Debugf("Writing Syft SBOM at %s: %+v", sbomPath, dep)
+	if err := dep.WriteTo(sbomPath); err != nil {
+		return fmt.Errorf("unable to write SBOM\n%w", err)
+	}
+	return nil
+}
+
+func (r MultiSolc) Name() string {
+	return r.LayerContributor.Name
+}
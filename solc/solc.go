@@ -16,24 +16,63 @@ package solc
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/buildpacks/libcnb"
 	"github.com/paketo-buildpacks/libpak"
 	"github.com/paketo-buildpacks/libpak/bard"
-	"github.com/paketo-buildpacks/libpak/crush"
 	"github.com/paketo-buildpacks/libpak/effect"
 	"github.com/paketo-buildpacks/libpak/sbom"
 	"github.com/paketo-buildpacks/libpak/sherpa"
 )
 
+// Distribution identifies which flavor of solc a Solc contributor installs.
+type Distribution string
+
+const (
+	// DistributionNative installs the official ethereum/solc-bin native binary for the
+	// detected OS/arch, invoking it directly rather than through a JS wrapper.
+	DistributionNative Distribution = "native"
+
+	// DistributionSolcJS installs solcjs via `npm install solc -g`.
+	DistributionSolcJS Distribution = "solcjs"
+)
+
+// DistributionFromEnvironment resolves the requested Distribution from
+// $BP_SOLC_DISTRIBUTION, defaulting to DistributionNative when unset or unrecognized.
+func DistributionFromEnvironment(env map[string]string) Distribution {
+	if Distribution(env["BP_SOLC_DISTRIBUTION"]) == DistributionSolcJS {
+		return DistributionSolcJS
+	}
+	return DistributionNative
+}
+
 type Solc struct {
 	LayerContributor libpak.DependencyLayerContributor
 	Logger           bard.Logger
 	Executor         effect.Executor
+	Distribution     Distribution
+
+	// SBOMFormats lists the SBOM media types the platform advertises via
+	// libcnb.BuildContext.Buildpack.Info.SBOMFormats. Emission of each format is
+	// skipped if it is not present here; when the list is empty, every format is emitted.
+	SBOMFormats []string
+
+	// NPMLock pins the npm-installed solc package to a known-good integrity hash, as
+	// parsed by ParseNPMLock. The solcjs distribution refuses to run if it is empty or
+	// does not contain an entry for the resolved version.
+	NPMLock []NPMPackage
 }
 
 func NewSolc(dependency libpak.BuildpackDependency, cache libpak.DependencyCache) Solc {
@@ -45,35 +84,60 @@ func NewSolc(dependency libpak.BuildpackDependency, cache libpak.DependencyCache
 	return Solc{
 		LayerContributor: contributor,
 		Executor:         effect.NewExecutor(),
+		Distribution:     DistributionSolcJS,
 	}
 }
 
+// NewNativeSolc creates a Solc contributor that installs the official solc-bin native
+// binary for the detected OS/arch instead of solcjs, bypassing the npm install path.
+func NewNativeSolc(dependency libpak.BuildpackDependency, cache libpak.DependencyCache) Solc {
+	solc := NewSolc(dependency, cache)
+	solc.Distribution = DistributionNative
+	return solc
+}
+
 func (r Solc) Contribute(layer libcnb.Layer) (libcnb.Layer, error) {
 	r.LayerContributor.Logger = r.Logger
-	return r.LayerContributor.Contribute(layer, func(artifact *os.File) (libcnb.Layer, error) {
-		bin := filepath.Join(layer.Path, "bin")
+	if r.Distribution == DistributionNative {
+		return r.contributeNative(layer)
+	}
+	return r.contributeSolcJS(layer)
+}
 
-		r.Logger.Bodyf("Expanding %s to %s", artifact.Name(), bin)
-		if err := crush.Extract(artifact, layer.Path, 1); err != nil {
-			return libcnb.Layer{}, fmt.Errorf("unable to expand %s\n%w", artifact.Name(), err)
+// contributeSolcJS installs the solcjs distribution from the fetched, SHA-256-verified
+// dependency artifact (the solc npm package tarball), globally via npm. Unlike the native
+// distribution, this dependency is not a standalone runtime to extract: it is installed
+// through `npm`, which must already be on $PATH (typically contributed by a Node.js
+// buildpack earlier in the build plan).
+func (r Solc) contributeSolcJS(layer libcnb.Layer) (libcnb.Layer, error) {
+	return r.LayerContributor.Contribute(layer, func(artifact *os.File) (libcnb.Layer, error) {
+		npm, err := exec.LookPath("npm")
+		if err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to locate npm on $PATH (the solcjs distribution requires a Node.js buildpack earlier in the build plan)\n%w", err)
 		}
 
-		r.Logger.Bodyf("Setting %s in PATH", bin)
-		if err := os.Setenv("PATH", sherpa.AppendToEnvVar("PATH", ":", bin)); err != nil {
-			return libcnb.Layer{}, fmt.Errorf("unable to set $PATH\n%w", err)
+		bin := filepath.Join(layer.Path, "bin")
+		if err := os.MkdirAll(bin, 0755); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to create %s\n%w", bin, err)
 		}
 
+		cache := filepath.Join(layer.Path, "npm-cache")
+		r.Logger.Bodyf("Installing %s globally via %s", artifact.Name(), npm)
 		buf := &bytes.Buffer{}
-		npm := filepath.Join(bin, "npm")
 		if err := r.Executor.Execute(effect.Execution{
 			Command: npm,
-			Args:    []string{"install", "solc", "-g"},
+			Args:    []string{"install", artifact.Name(), "-g", "--prefix", layer.Path, "--cache", cache},
 			Stdout:  buf,
 			Stderr:  buf,
 		}); err != nil {
 			return libcnb.Layer{}, fmt.Errorf("error executing '%s':\n Combined Output: %s: \n%w", npm, buf.String(), err)
 		}
 
+		r.Logger.Bodyf("Setting %s in PATH", bin)
+		if err := os.Setenv("PATH", sherpa.AppendToEnvVar("PATH", ":", bin)); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to set $PATH\n%w", err)
+		}
+
 		buf = &bytes.Buffer{}
 		if err := r.Executor.Execute(effect.Execution{
 			Command: "solcjs",
@@ -86,6 +150,200 @@ func (r Solc) Contribute(layer libcnb.Layer) (libcnb.Layer, error) {
 		version := strings.TrimSpace(buf.String())
 		r.Logger.Bodyf("Checking solc version: %s", version)
 
+		installed := filepath.Join(layer.Path, "lib", "node_modules", "solc")
+		if _, err := os.Stat(installed); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to locate installed solc package at %s\n%w", installed, err)
+		}
+		if err := r.verifyNPMIntegrity("solc", version, cache); err != nil {
+			return libcnb.Layer{}, err
+		}
+		if err := verifyInstalledTree(layer.Path, cache); err != nil {
+			r.Logger.Bodyf("Integrity violation in installed node_modules tree: %s", err)
+			return libcnb.Layer{}, err
+		}
+
+		return r.writeSBOM(layer, version)
+	})
+}
+
+// verifyNPMIntegrity checks name@version against the pinned entry in r.NPMLock,
+// refusing to proceed if the version is unpinned, and otherwise verifying that the
+// tarball npm actually fetched into cache matches the pinned integrity hash.
+func (r Solc) verifyNPMIntegrity(name string, version string, cache string) error {
+	pkg, ok := Find(r.NPMLock, name, coreVersion(version))
+	if !ok {
+		return fmt.Errorf("refusing to use %s@%s: no pinned entry in [metadata.npm-packages]", name, version)
+	}
+
+	if err := verifyCacheEntry(cache, pkg.Integrity); err != nil {
+		r.Logger.Bodyf("Integrity violation for %s@%s: %s", name, version, err)
+		return fmt.Errorf("integrity violation for %s@%s\n%w", name, version, err)
+	}
+	return nil
+}
+
+// coreVersion strips solc's build metadata (e.g. the "+commit.b61c2a91.Linux.g++"
+// suffix reported by `solcjs --version`) down to the bare X.Y.Z recorded in
+// [metadata.npm-packages].
+func coreVersion(version string) string {
+	v, err := semver.NewVersion(strings.SplitN(version, "+", 2)[0])
+	if err != nil {
+		return version
+	}
+	return fmt.Sprintf("%d.%d.%d", v.Major(), v.Minor(), v.Patch())
+}
+
+// verifyCacheEntry recomputes the SHA-512 digest of the tarball npm fetched into its
+// content-addressable cache at cache/_cacache, without re-fetching anything from the
+// network, and checks it against the pinned `sha512-<base64>` integrity string.
+func verifyCacheEntry(cache string, integrity string) error {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(integrity, "sha512-"))
+	if err != nil {
+		return fmt.Errorf("invalid integrity %q\n%w", integrity, err)
+	}
+	expected := hex.EncodeToString(raw)
+
+	path := filepath.Join(cache, "_cacache", "content-v2", "sha512", expected[0:2], expected[2:4], expected[4:])
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("solc tarball not found in npm cache at %s\n%w", path, err)
+	}
+	defer f.Close()
+
+	hasher := sha512.New()
+	if _, err := io.Copy(hasher, f); err != nil {
+		return fmt.Errorf("unable to read %s\n%w", path, err)
+	}
+
+	if actual := hex.EncodeToString(hasher.Sum(nil)); actual != expected {
+		return fmt.Errorf("expected sha512-%s, actual sha512-%s", expected, actual)
+	}
+	return nil
+}
+
+// packageLockEntry is one entry of npm's auto-generated node_modules/.package-lock.json,
+// the hidden lockfile npm (v7+) writes to record exactly what it resolved for every
+// package in the tree, including transitive dependencies.
+type packageLockEntry struct {
+	Version   string `json:"version"`
+	Integrity string `json:"integrity"`
+}
+
+// verifyInstalledTree walks every package recorded in npm's own
+// node_modules/.package-lock.json under prefix and verifies each one's integrity against
+// the npm cache, so a tampered or corrupted transitive dependency (not just the pinned
+// top-level solc package checked by verifyNPMIntegrity) fails the build.
+func verifyInstalledTree(prefix string, cache string) error {
+	lockPath := filepath.Join(prefix, "lib", "node_modules", ".package-lock.json")
+	raw, err := os.ReadFile(lockPath)
+	if err != nil {
+		return fmt.Errorf("unable to read %s\n%w", lockPath, err)
+	}
+
+	var doc struct {
+		Packages map[string]packageLockEntry `json:"packages"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return fmt.Errorf("unable to parse %s\n%w", lockPath, err)
+	}
+
+	for path, pkg := range doc.Packages {
+		if pkg.Integrity == "" {
+			continue
+		}
+		if err := verifyCacheEntry(cache, pkg.Integrity); err != nil {
+			return fmt.Errorf("integrity violation for %s@%s\n%w", path, pkg.Version, err)
+		}
+	}
+	return nil
+}
+
+// contributeNative installs the raw solc-bin native binary (no archive, no npm) at
+// bin/solc, verifying its SHA-256 against the dependency declared in buildpack.toml.
+func (r Solc) contributeNative(layer libcnb.Layer) (libcnb.Layer, error) {
+	return r.LayerContributor.Contribute(layer, func(artifact *os.File) (libcnb.Layer, error) {
+		bin := filepath.Join(layer.Path, "bin")
+		if err := os.MkdirAll(bin, 0755); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to create %s\n%w", bin, err)
+		}
+
+		solc := filepath.Join(bin, "solc")
+		r.Logger.Bodyf("Installing native solc binary to %s", solc)
+
+		out, err := os.OpenFile(solc, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0755)
+		if err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to open %s\n%w", solc, err)
+		}
+		defer out.Close()
+
+		hasher := sha256.New()
+		if _, err := io.Copy(out, io.TeeReader(artifact, hasher)); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to write %s\n%w", solc, err)
+		}
+
+		if expected := strings.ToLower(r.LayerContributor.Dependency.SHA256); expected != "" {
+			if actual := hex.EncodeToString(hasher.Sum(nil)); actual != expected {
+				return libcnb.Layer{}, fmt.Errorf("solc binary checksum mismatch: expected %s, actual %s", expected, actual)
+			}
+		}
+
+		r.Logger.Bodyf("Setting %s in PATH", bin)
+		if err := os.Setenv("PATH", sherpa.AppendToEnvVar("PATH", ":", bin)); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to set $PATH\n%w", err)
+		}
+
+		buf := &bytes.Buffer{}
+		if err := r.Executor.Execute(effect.Execution{
+			Command: solc,
+			Args:    []string{"--version"},
+			Stdout:  buf,
+			Stderr:  buf,
+		}); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("error executing 'solc --version':\n Combined Output: %s: \n%w", buf.String(), err)
+		}
+		version := parseNativeVersion(buf.String())
+		r.Logger.Bodyf("Checking solc version: %s", version)
+
+		return r.writeSBOM(layer, version)
+	})
+}
+
+// parseNativeVersion extracts the semantic version from `solc --version` output, which
+// (unlike solcjs) reports it on a dedicated "Version: " line rather than alone.
+func parseNativeVersion(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		if v, ok := strings.CutPrefix(strings.TrimSpace(line), "Version: "); ok {
+			return v
+		}
+	}
+	return strings.TrimSpace(output)
+}
+
+func (r Solc) purl(version string) string {
+	if r.Distribution == DistributionNative {
+		return fmt.Sprintf("pkg:generic/solc@%s", version)
+	}
+	return fmt.Sprintf("pkg:npm/solc@%s", version)
+}
+
+func (r Solc) emitsFormat(format libcnb.SBOMFormat) bool {
+	if len(r.SBOMFormats) == 0 {
+		return true
+	}
+	for _, f := range r.SBOMFormats {
+		if f == format.MediaType() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Solc) cpe(version string) string {
+	return fmt.Sprintf("cpe:2.3:a:solc:solc:%s:*:*:*:*:*:*:*", version)
+}
+
+func (r Solc) writeSBOM(layer libcnb.Layer, version string) (libcnb.Layer, error) {
+	if r.emitsFormat(libcnb.SyftJSON) {
 		sbomPath := layer.SBOMPath(libcnb.SyftJSON)
 		dep := sbom.NewSyftDependency(layer.Path, []sbom.SyftArtifact{
 			{
@@ -98,22 +356,53 @@ func (r Solc) Contribute(layer libcnb.Layer) (libcnb.Layer, error) {
 					{Path: "amp-buildpacks/solc/solc/solc.go"},
 				},
 				Licenses: []string{"Apache-2.0"},
-				CPEs:     []string{fmt.Sprintf("cpe:2.3:a:solc:solc:%s:*:*:*:*:*:*:*", version)},
-				PURL:     fmt.Sprintf("pkg:generic/solc@%s", version),
+				CPEs:     []string{r.cpe(version)},
+				PURL:     r.purl(version),
 			},
 		})
 		r.Logger.Debugf("Writing Syft SBOM at %s: %+v", sbomPath, dep)
 		if err := dep.WriteTo(sbomPath); err != nil {
 			return libcnb.Layer{}, fmt.Errorf("unable to write SBOM\n%w", err)
 		}
-		return layer, nil
-	})
+	}
+
+	if r.emitsFormat(libcnb.CycloneDXJSON) {
+		sbomPath := layer.SBOMPath(libcnb.CycloneDXJSON)
+		purl := r.purl(version)
+		bom := cycloneDXBOM{
+			BOMFormat:   "CycloneDX",
+			SpecVersion: "1.4",
+			Version:     1,
+			Metadata: cycloneDXMetadata{
+				Tools: []cycloneDXTool{
+					{Vendor: "amp-buildpacks", Name: "amp-buildpacks/solc"},
+				},
+			},
+			Components: []cycloneDXComponent{
+				{
+					BOMRef:  purl,
+					Type:    "application",
+					Name:    "solc",
+					Version: version,
+					PURL:    purl,
+					CPE:     r.cpe(version),
+				},
+			},
+		}
+
+		r.Logger.Debugf("Writing CycloneDX SBOM at %s: %+v", sbomPath, bom)
+		if err := bom.WriteTo(sbomPath); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to write SBOM\n%w", err)
+		}
+	}
+
+	return layer, nil
 }
 
 func (r Solc) BuildProcessTypes(enableProcess string) ([]libcnb.Process, error) {
 	processes := []libcnb.Process{}
 
-	if enableProcess == "true" {
+	if enableProcess == "true" && r.Distribution != DistributionNative {
 		processes = append(processes, libcnb.Process{
 			Type:    "web",
 			Command: "npm start",